@@ -19,12 +19,16 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	api "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/registry"
@@ -32,18 +36,34 @@ import (
 	"k8s.io/kops/pkg/nodeidentity"
 	"k8s.io/kops/pkg/nodelabels"
 	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kops/util/pkg/vfs/watch"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
-// NewLegacyNodeReconciler is the constructor for a LegacyNodeReconciler
-func NewLegacyNodeReconciler(mgr manager.Manager, vfsContext *vfs.VFSContext, configPath string, identifier nodeidentity.LegacyIdentifier) (*LegacyNodeReconciler, error) {
+// NewLegacyNodeReconciler is the constructor for a LegacyNodeReconciler.
+// extraSources, if any, are merged after the built-in sources (InstanceGroup
+// labels, then cloud metadata); see nodelabels.Source. Pass
+// nodelabels.NewNodeLabelPolicySource(mgr.GetClient()) as an extraSource to
+// opt in to NodeLabelPolicy, but only after calling nodelabels.AddToScheme
+// against mgr.GetScheme() and installing the CRD - it isn't part of the
+// built-in pipeline because neither is guaranteed to be true.
+func NewLegacyNodeReconciler(mgr manager.Manager, vfsContext *vfs.VFSContext, configPath string, identifier nodeidentity.LegacyIdentifier, extraSources ...nodelabels.Source) (*LegacyNodeReconciler, error) {
 	r := &LegacyNodeReconciler{
-		client:     mgr.GetClient(),
-		log:        ctrl.Log.WithName("controllers").WithName("Node"),
-		identifier: identifier,
-		cache:      vfs.NewCache(),
+		client:       mgr.GetClient(),
+		log:          ctrl.Log.WithName("controllers").WithName("Node"),
+		identifier:   identifier,
+		cache:        vfs.NewCache(),
+		watcher:      watch.NewWatcher(watch.DefaultResyncInterval),
+		nodesByIG: make(map[string]map[types.NamespacedName]bool),
+		// Buffered so a burst of Nodes fanned out from a single IG change doesn't
+		// block the watcher's poll goroutine on a slow source.Channel consumer.
+		igRequests:   make(chan event.GenericEvent, 64),
+		extraSources: extraSources,
 	}
 
 	coreClient, err := corev1client.NewForConfig(mgr.GetConfig())
@@ -51,6 +71,7 @@ func NewLegacyNodeReconciler(mgr manager.Manager, vfsContext *vfs.VFSContext, co
 		return nil, fmt.Errorf("error building corev1 client: %v", err)
 	}
 	r.coreV1Client = coreClient
+	r.recorder = mgr.GetEventRecorderFor("node-controller")
 
 	configBase, err := vfsContext.BuildVfsPath(configPath)
 	if err != nil {
@@ -73,14 +94,65 @@ type LegacyNodeReconciler struct {
 	// coreV1Client is a client-go client for patching nodes
 	coreV1Client *corev1client.CoreV1Client
 
+	// recorder emits Events against Nodes, currently only used by the startup
+	// label migration.
+	recorder record.EventRecorder
+
 	// identifier is a provider that can securely map node ProviderIDs to InstanceGroups
 	identifier nodeidentity.LegacyIdentifier
 
 	// configBase is the parsed path to the base location of our configuration files
 	configBase vfs.Path
 
-	// cache caches the instancegroup and cluster values, to avoid repeated GCS/S3 calls
+	// cache caches the instancegroup and cluster values, to avoid repeated GCS/S3 calls.
+	// Entries are invalidated by watcher as soon as a change is observed, rather than
+	// purely relying on the cache's TTL.
 	cache *vfs.Cache
+
+	// watcher notices when a Cluster or InstanceGroup object changes in the backing
+	// vfs.Path, so that we can invalidate cache and enqueue affected Nodes immediately
+	// instead of waiting for the cache TTL to expire.
+	watcher *watch.Watcher
+
+	// nodesByIG indexes the Nodes we have most recently reconciled by the name of the
+	// InstanceGroup that owns them, so that a single InstanceGroup change can be fanned
+	// out to every affected Node.
+	mu        sync.Mutex
+	nodesByIG map[string]map[types.NamespacedName]bool
+
+	// igRequests is fed GenericEvents by watcher callbacks, and is wired into
+	// SetupWithManager via a source.Channel so that config changes trigger Reconcile.
+	igRequests chan event.GenericEvent
+
+	// watchedIGs tracks which InstanceGroup paths we have already registered with
+	// watcher, so that we only watch each one once.
+	watchedIGs sync.Map
+
+	// ManagedTaintPrefixes lists the taint key prefixes this reconciler is
+	// authoritative for. Reconcile only removes taints under these prefixes, so
+	// that cluster-autoscaler- or user-applied taints are not pruned; taints the
+	// InstanceGroup declares are added or updated whatever their key.
+	// Defaults to []string{nodelabels.ManagedTaintNamespace} if unset.
+	ManagedTaintPrefixes []string
+
+	// MigrateLabels gates the one-time startup migration (see migrateLabels) that
+	// prunes nodelabels.DeprecatedManagedLabels from every Node. Wired up from the
+	// --migrate-labels flag.
+	MigrateLabels bool
+
+	// extraSources holds any nodelabels.Source passed to NewLegacyNodeReconciler
+	// beyond the built-in ones; see sources().
+	extraSources []nodelabels.Source
+}
+
+// sources returns the full, ordered label-source pipeline: the built-in
+// sources, followed by any extraSources the caller supplied.
+func (r *LegacyNodeReconciler) sources() []nodelabels.Source {
+	sources := []nodelabels.Source{
+		nodelabels.InstanceGroupSource{},
+		nodelabels.CloudMetadataSource{},
+	}
+	return append(sources, r.extraSources...)
 }
 
 // +kubebuilder:rbac:groups=,resources=nodes,verbs=get;list;watch;patch
@@ -105,23 +177,26 @@ func (r *LegacyNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, fmt.Errorf("unable to load cluster object for node %s: %v", node.Name, err)
 	}
 
-	ig, err := r.getInstanceGroupForNode(ctx, node)
+	identity, err := r.identifier.IdentifyNode(ctx, node)
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("unable to load instance group object for node %s: %v", node.Name, err)
+		return ctrl.Result{}, fmt.Errorf("error identifying node %q: %v", node.Name, err)
 	}
 
-	labels, err := nodelabels.BuildNodeLabels(cluster, ig)
+	ig, err := r.getInstanceGroupForNode(node, identity)
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("error building node labels for node %q: %w", node.Name, err)
+		return ctrl.Result{}, fmt.Errorf("unable to load instance group object for node %s: %v", node.Name, err)
 	}
 
-	lifecycle, err := r.getInstanceLifecycle(ctx, node)
+	r.rememberNodeForIG(req.NamespacedName, ig.Name)
+	r.watchInstanceGroup(ctx, ig.Name)
+
+	labels, err := nodelabels.MergeSources(ctx, node, cluster, ig, identity, r.sources())
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("unable to get instance lifecycle %s: %v", node.Name, err)
+		return ctrl.Result{}, fmt.Errorf("error building node labels for node %q: %w", node.Name, err)
 	}
 
-	if len(lifecycle) > 0 {
-		labels[fmt.Sprintf("node-role.kubernetes.io/%s-worker", lifecycle)] = "true"
+	if identity.InstanceLifecycle != "" {
+		labels[fmt.Sprintf("node-role.kubernetes.io/%s-worker", identity.InstanceLifecycle)] = "true"
 	}
 
 	updateLabels := make(map[string]string)
@@ -143,25 +218,175 @@ func (r *LegacyNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}
 
-	if len(updateLabels) == 0 && len(deleteLabels) == 0 {
-		klog.V(4).Infof("no label changes needed for %s", node.Name)
+	// BuildNodeAnnotations is an extension point for future annotation sources
+	// (topology hints, lifecycle metadata); it contributes no annotations today,
+	// so we only apply additions/updates here, not deletions. Pruning user- or
+	// operator-set annotations on the strength of an always-empty desired set
+	// would delete whatever anyone else happened to put under the managed taint
+	// namespace, so that's deferred until there's a real annotation source with
+	// its own declared prefix to prune against.
+	annotations, err := nodelabels.BuildNodeAnnotations(cluster, ig)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("error building node annotations for node %q: %w", node.Name, err)
+	}
+
+	updateAnnotations := make(map[string]string)
+	for k, v := range annotations {
+		actual, found := node.Annotations[k]
+		if !found || actual != v {
+			updateAnnotations[k] = v
+		}
+	}
+
+	taints, err := nodelabels.BuildNodeTaints(cluster, ig)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("error building node taints for node %q: %w", node.Name, err)
+	}
+
+	patch := &nodePatch{
+		updateLabels:      updateLabels,
+		deleteLabels:      deleteLabels,
+		updateAnnotations: updateAnnotations,
+		taints:            taints,
+		managedTaints:     r.managedTaintKeys(node.Spec.Taints),
+	}
+
+	if patch.isEmpty(node) {
+		klog.V(4).Infof("no changes needed for %s", node.Name)
 		return ctrl.Result{}, nil
 	}
 
-	if err := patchNodeLabels(r.coreV1Client, ctx, node, updateLabels, deleteLabels); err != nil {
-		klog.Warningf("failed to patch node labels on %s: %v", node.Name, err)
+	if err := patchNode(r.coreV1Client, ctx, node, patch); err != nil {
+		klog.Warningf("failed to patch node %s: %v", node.Name, err)
 		return ctrl.Result{}, err
 	}
 
 	return ctrl.Result{}, nil
 }
 
+// managedTaintPrefixes returns r.ManagedTaintPrefixes, or a default of just the
+// kops-owned taint namespace if the operator hasn't configured any.
+func (r *LegacyNodeReconciler) managedTaintPrefixes() []string {
+	if len(r.ManagedTaintPrefixes) > 0 {
+		return r.ManagedTaintPrefixes
+	}
+	return []string{nodelabels.ManagedTaintNamespace}
+}
+
+// isManagedTaintKey reports whether key falls under one of the reconciler's
+// managed taint prefixes.
+func (r *LegacyNodeReconciler) isManagedTaintKey(key string) bool {
+	for _, prefix := range r.managedTaintPrefixes() {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// managedTaintKeys returns the set of existing taint key+effects (from
+// existingTaints) that fall under one of the reconciler's managed taint prefixes,
+// and are therefore candidates for update or removal by Reconcile.
+func (r *LegacyNodeReconciler) managedTaintKeys(existingTaints []corev1.Taint) map[string]bool {
+	managed := make(map[string]bool)
+	for _, t := range existingTaints {
+		if r.isManagedTaintKey(t.Key) {
+			managed[nodelabels.TaintKey(t)] = true
+		}
+	}
+	return managed
+}
+
 func (r *LegacyNodeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Watch the cluster config eagerly; InstanceGroups are watched lazily, the first
+	// time we see a Node that belongs to them, since we don't otherwise know their names.
+	r.watchCluster(context.Background())
+
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		r.watcher.Start(ctx)
+		return nil
+	})); err != nil {
+		return fmt.Errorf("error registering vfs watcher: %v", err)
+	}
+
+	if r.MigrateLabels {
+		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			return r.migrateLabels(ctx)
+		})); err != nil {
+			return fmt.Errorf("error registering label migration: %v", err)
+		}
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.Node{}).
+		For(&corev1.Node{}, builder.WithPredicates(nodeReconcilePredicate())).
+		WatchesRawSource(&source.Channel{Source: r.igRequests}).
 		Complete(r)
 }
 
+// watchCluster registers the cluster config path with watcher, invalidating the
+// cache entry as soon as a change is observed so that the next reconcile re-reads it.
+func (r *LegacyNodeReconciler) watchCluster(ctx context.Context) {
+	p := r.configBase.Join(registry.PathClusterCompleted)
+	r.watcher.Watch(p, func(ctx context.Context, p vfs.Path) {
+		r.cache.Invalidate(p)
+	})
+}
+
+// watchInstanceGroup registers the named InstanceGroup's config path with watcher
+// (once), invalidating its cache entry and enqueuing every Node we know belongs to
+// it whenever a change is observed, so config edits propagate immediately instead
+// of waiting for the cache TTL.
+func (r *LegacyNodeReconciler) watchInstanceGroup(ctx context.Context, name string) {
+	if _, alreadyWatched := r.watchedIGs.LoadOrStore(name, true); alreadyWatched {
+		return
+	}
+
+	p := r.configBase.Join("instancegroup", name)
+	r.watcher.Watch(p, func(ctx context.Context, p vfs.Path) {
+		r.cache.Invalidate(p)
+		r.enqueueNodesForIG(ctx, name)
+	})
+}
+
+// rememberNodeForIG records that nodeName currently belongs to InstanceGroup ig, so
+// that a future config change to ig can be fanned out to it.
+func (r *LegacyNodeReconciler) rememberNodeForIG(nodeName types.NamespacedName, ig string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	nodes, ok := r.nodesByIG[ig]
+	if !ok {
+		nodes = make(map[types.NamespacedName]bool)
+		r.nodesByIG[ig] = nodes
+	}
+	nodes[nodeName] = true
+}
+
+// enqueueNodesForIG sends a GenericEvent for every Node known to belong to ig,
+// which the source.Channel wired up in SetupWithManager turns into a Reconcile
+// call. Once igRequests' buffer is full, each send blocks the watcher's poll
+// goroutine until the consumer takes an event or ctx is done, at which point
+// the remaining Nodes are not enqueued.
+func (r *LegacyNodeReconciler) enqueueNodesForIG(ctx context.Context, ig string) {
+	r.mu.Lock()
+	nodes := make([]types.NamespacedName, 0, len(r.nodesByIG[ig]))
+	for nodeName := range r.nodesByIG[ig] {
+		nodes = append(nodes, nodeName)
+	}
+	r.mu.Unlock()
+
+	for _, nodeName := range nodes {
+		node := &corev1.Node{}
+		node.Name = nodeName.Name
+		node.Namespace = nodeName.Namespace
+		select {
+		case r.igRequests <- event.GenericEvent{Object: node}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // getClusterForNode returns the api.Cluster object for the node
 // The cluster is actually loaded when we first start
 func (r *LegacyNodeReconciler) getClusterForNode(node *corev1.Node) (*api.Cluster, error) {
@@ -173,33 +398,17 @@ func (r *LegacyNodeReconciler) getClusterForNode(node *corev1.Node) (*api.Cluste
 	return cluster, nil
 }
 
-// getInstanceLifecycle returns InstanceLifecycle string object
-func (r *LegacyNodeReconciler) getInstanceLifecycle(ctx context.Context, node *corev1.Node) (string, error) {
-	identity, err := r.identifier.IdentifyNode(ctx, node)
-	if err != nil {
-		return "", fmt.Errorf("error identifying node %q: %v", node.Name, err)
-	}
-
-	return identity.InstanceLifecycle, nil
-}
-
 // getInstanceGroupForNode returns the api.InstanceGroup object for the node
-func (r *LegacyNodeReconciler) getInstanceGroupForNode(ctx context.Context, node *corev1.Node) (*api.InstanceGroup, error) {
+func (r *LegacyNodeReconciler) getInstanceGroupForNode(node *corev1.Node, identity *nodeidentity.Info) (*api.InstanceGroup, error) {
 	// We assume that if the instancegroup label is set, that it is correct
 	// TODO: Should we be paranoid?
 	instanceGroupName := node.Labels["kops.k8s.io/instancegroup"]
 
 	if instanceGroupName == "" {
-		providerID := node.Spec.ProviderID
-		if providerID == "" {
+		if node.Spec.ProviderID == "" {
 			return nil, fmt.Errorf("node providerID not set for node %q", node.Name)
 		}
 
-		identity, err := r.identifier.IdentifyNode(ctx, node)
-		if err != nil {
-			return nil, fmt.Errorf("error identifying node %q: %v", node.Name, err)
-		}
-
 		if identity.InstanceGroup == "" {
 			return nil, fmt.Errorf("node %q did not have an associate instance group", node.Name)
 		}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/nodelabels"
+)
+
+// labelSchemaVersionAnnotation records the nodelabels.LabelSchemaVersion a Node was
+// last migrated to, so that migrateLabels can skip Nodes that are already current
+// without having to recompute and diff their labels on every controller startup.
+const labelSchemaVersionAnnotation = "kops.k8s.io/label-schema-version"
+
+// migrateLabels lists every Node once and, for any that aren't already at the
+// current nodelabels.LabelSchemaVersion, prunes the deprecated label keys in
+// nodelabels.DeprecatedManagedLabels and records the schema version. It is
+// intended to run once at controller startup (gated by the --migrate-labels flag)
+// so that Nodes labeled by an older kops-controller don't have to wait for an
+// unrelated reconcile to shed stale labels. It is safe to run repeatedly: a Node
+// already at the current schema version is skipped entirely.
+func (r *LegacyNodeReconciler) migrateLabels(ctx context.Context) error {
+	var nodes corev1.NodeList
+	if err := r.client.List(ctx, &nodes); err != nil {
+		return fmt.Errorf("error listing nodes for label migration: %w", err)
+	}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+
+		if node.Annotations[labelSchemaVersionAnnotation] == nodelabels.LabelSchemaVersion {
+			continue
+		}
+
+		if err := r.migrateNodeLabels(ctx, node); err != nil {
+			klog.Warningf("error migrating labels for node %s, will retry on next restart: %v", node.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// migrateNodeLabels prunes node's deprecated labels (if any are present) and
+// stamps it with the current label schema version, in a single patch.
+func (r *LegacyNodeReconciler) migrateNodeLabels(ctx context.Context, node *corev1.Node) error {
+	deleteLabels := make(map[string]struct{})
+	for _, key := range nodelabels.DeprecatedManagedLabels {
+		if _, found := node.Labels[key]; found {
+			deleteLabels[key] = struct{}{}
+		}
+	}
+
+	patch := &nodePatch{
+		deleteLabels: deleteLabels,
+		updateAnnotations: map[string]string{
+			labelSchemaVersionAnnotation: nodelabels.LabelSchemaVersion,
+		},
+	}
+
+	if err := patchNode(r.coreV1Client, ctx, node, patch); err != nil {
+		return fmt.Errorf("error patching node %q: %w", node.Name, err)
+	}
+
+	if len(deleteLabels) > 0 && r.recorder != nil {
+		r.recorder.Eventf(node, corev1.EventTypeNormal, "LabelSchemaMigrated", "removed deprecated managed labels %v", keys(deleteLabels))
+	}
+
+	return nil
+}
+
+func keys(m map[string]struct{}) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
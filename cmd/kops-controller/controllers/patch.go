@@ -0,0 +1,167 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/kops/pkg/nodelabels"
+)
+
+// nodePatch describes the labels, annotations and taints we want to converge a
+// Node to. Zero-value fields mean "no change requested" for that category.
+type nodePatch struct {
+	updateLabels map[string]string
+	deleteLabels map[string]struct{}
+
+	updateAnnotations map[string]string
+	deleteAnnotations map[string]struct{}
+
+	// taints is the full desired set of taints; each one replaces (rather than
+	// merges with) any existing taint sharing the same key+effect, managed or
+	// not, and existing taints listed in managedTaints but absent from taints
+	// are removed. Existing taints that are neither in taints nor in
+	// managedTaints are left untouched.
+	taints        []corev1.Taint
+	managedTaints map[string]bool
+}
+
+// isEmpty reports whether the patch has nothing to do.
+func (p *nodePatch) isEmpty(node *corev1.Node) bool {
+	if len(p.updateLabels) != 0 || len(p.deleteLabels) != 0 {
+		return false
+	}
+	if len(p.updateAnnotations) != 0 || len(p.deleteAnnotations) != 0 {
+		return false
+	}
+	return !taintsDiffer(node.Spec.Taints, p.taints, p.managedTaints)
+}
+
+// taintsDiffer reports whether applying the desired taints (for the managed
+// keys) to existing would actually change anything.
+func taintsDiffer(existing, desired []corev1.Taint, managed map[string]bool) bool {
+	return !equalTaintSets(mergeTaints(existing, desired, managed), existing)
+}
+
+// mergeTaints returns existing with every taint present in desired (managed or
+// not) updated to match the entry in desired, and any managed taint not
+// present in desired removed. Taints we're not authoritative for (per managed)
+// are only ever updated or left alone, never removed, since an unmanaged key
+// missing from desired just means we don't track it, not that it should be
+// dropped from the Node.
+func mergeTaints(existing, desired []corev1.Taint, managed map[string]bool) []corev1.Taint {
+	desiredByKey := make(map[string]corev1.Taint, len(desired))
+	for _, t := range desired {
+		desiredByKey[nodelabels.TaintKey(t)] = t
+	}
+
+	var merged []corev1.Taint
+	for _, t := range existing {
+		key := nodelabels.TaintKey(t)
+		if d, ok := desiredByKey[key]; ok {
+			// Present in desired, managed or not: take the desired value so an
+			// IG taint's value can be updated, not just added once and left stale.
+			merged = append(merged, d)
+			delete(desiredByKey, key)
+			continue
+		}
+		if !managed[key] {
+			merged = append(merged, t)
+			continue
+		}
+		// else: we own this taint and it's no longer desired, so drop it.
+	}
+	for _, t := range desired {
+		if _, stillPending := desiredByKey[nodelabels.TaintKey(t)]; stillPending {
+			merged = append(merged, t)
+		}
+	}
+	return merged
+}
+
+func equalTaintSets(a, b []corev1.Taint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	byKey := make(map[string]corev1.Taint, len(a))
+	for _, t := range a {
+		byKey[nodelabels.TaintKey(t)] = t
+	}
+	for _, t := range b {
+		other, ok := byKey[nodelabels.TaintKey(t)]
+		if !ok || other.Value != t.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// patchNode applies the requested label, annotation and taint changes to node
+// as a single strategic-merge patch.
+func patchNode(client *corev1client.CoreV1Client, ctx context.Context, node *corev1.Node, p *nodePatch) error {
+	if p.isEmpty(node) {
+		return nil
+	}
+
+	nodeCopy := node.DeepCopy()
+
+	if nodeCopy.Labels == nil {
+		nodeCopy.Labels = make(map[string]string)
+	}
+	for k := range p.deleteLabels {
+		delete(nodeCopy.Labels, k)
+	}
+	for k, v := range p.updateLabels {
+		nodeCopy.Labels[k] = v
+	}
+
+	if nodeCopy.Annotations == nil {
+		nodeCopy.Annotations = make(map[string]string)
+	}
+	for k := range p.deleteAnnotations {
+		delete(nodeCopy.Annotations, k)
+	}
+	for k, v := range p.updateAnnotations {
+		nodeCopy.Annotations[k] = v
+	}
+
+	nodeCopy.Spec.Taints = mergeTaints(node.Spec.Taints, p.taints, p.managedTaints)
+
+	oldData, err := json.Marshal(node)
+	if err != nil {
+		return fmt.Errorf("error marshalling node %q: %w", node.Name, err)
+	}
+	newData, err := json.Marshal(nodeCopy)
+	if err != nil {
+		return fmt.Errorf("error marshalling updated node %q: %w", node.Name, err)
+	}
+
+	patchBytes, err := strategicpatch.CreateTwoWayMergePatch(oldData, newData, &corev1.Node{})
+	if err != nil {
+		return fmt.Errorf("error building patch for node %q: %w", node.Name, err)
+	}
+
+	_, err = client.Nodes().Patch(ctx, node.Name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+	return err
+}
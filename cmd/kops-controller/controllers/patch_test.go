@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMergeTaintsDoesNotDuplicateUnmanagedTaint(t *testing.T) {
+	existing := []corev1.Taint{
+		{Key: "dedicated", Value: "ml", Effect: corev1.TaintEffectNoSchedule},
+	}
+	// desired is unchanged from what's already on the node, as it would be on
+	// every reconcile after the first successful apply.
+	desired := []corev1.Taint{
+		{Key: "dedicated", Value: "ml", Effect: corev1.TaintEffectNoSchedule},
+	}
+	managed := map[string]bool{} // "dedicated" isn't under a managed taint prefix
+
+	merged := mergeTaints(existing, desired, managed)
+
+	if len(merged) != 1 {
+		t.Fatalf("expected exactly one taint, got %d: %v", len(merged), merged)
+	}
+}
+
+func TestMergeTaintsAddsNewManagedTaint(t *testing.T) {
+	var existing []corev1.Taint
+	desired := []corev1.Taint{
+		{Key: "node.kops.k8s.io/pending", Value: "", Effect: corev1.TaintEffectNoSchedule},
+	}
+	managed := map[string]bool{}
+
+	merged := mergeTaints(existing, desired, managed)
+
+	if len(merged) != 1 || merged[0].Key != "node.kops.k8s.io/pending" {
+		t.Fatalf("expected the new taint to be added, got %v", merged)
+	}
+}
+
+func TestMergeTaintsUpdatesUnmanagedTaintValue(t *testing.T) {
+	existing := []corev1.Taint{
+		{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoSchedule},
+	}
+	// The IG's Taints value changed from "a" to "b"; key+effect are unchanged.
+	desired := []corev1.Taint{
+		{Key: "dedicated", Value: "b", Effect: corev1.TaintEffectNoSchedule},
+	}
+	managed := map[string]bool{} // "dedicated" isn't under a managed taint prefix
+
+	merged := mergeTaints(existing, desired, managed)
+
+	if len(merged) != 1 || merged[0].Value != "b" {
+		t.Fatalf("expected the taint value to be updated to %q, got %v", "b", merged)
+	}
+}
+
+func TestMergeTaintsRemovesManagedTaintNoLongerDesired(t *testing.T) {
+	existing := []corev1.Taint{
+		{Key: "node.kops.k8s.io/pending", Value: "", Effect: corev1.TaintEffectNoSchedule},
+	}
+	var desired []corev1.Taint
+	managed := map[string]bool{"node.kops.k8s.io/pending:NoSchedule": true}
+
+	merged := mergeTaints(existing, desired, managed)
+
+	if len(merged) != 0 {
+		t.Fatalf("expected the managed taint to be removed, got %v", merged)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kops/pkg/nodelabels"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// nodeReconcilePredicate skips Node events that can't change the outcome of
+// Reconcile, so that kubelet heartbeats and unrelated status updates don't
+// trigger a VFS read and an IdentifyNode call. We fold a
+// GenerationChangedPredicate-style check into the same predicate.Funcs,
+// rather than composing with predicate.Or, because Or would also adopt
+// GenerationChangedPredicate's default "true" for Delete events and
+// reintroduce the no-op reconciles on Node deletion that we're trying to
+// eliminate here.
+func nodeReconcilePredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return true
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			// Reconcile already no-ops on NotFound, so there's nothing for us to do.
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			// The Node watch this predicate is attached to doesn't emit GenericEvents;
+			// the ones we synthesize from igRequests arrive through a separate
+			// source.Channel that this predicate isn't applied to.
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, ok := e.ObjectOld.(*corev1.Node)
+			if !ok {
+				return true
+			}
+			newNode, ok := e.ObjectNew.(*corev1.Node)
+			if !ok {
+				return true
+			}
+
+			if oldNode.GetGeneration() != newNode.GetGeneration() {
+				return true
+			}
+			if oldNode.Spec.ProviderID != newNode.Spec.ProviderID {
+				return true
+			}
+			if oldNode.Labels["kops.k8s.io/instancegroup"] != newNode.Labels["kops.k8s.io/instancegroup"] {
+				return true
+			}
+			return managedLabelsChanged(oldNode.Labels, newNode.Labels)
+		},
+	}
+}
+
+// managedLabelsChanged reports whether any label that kOps manages (the static
+// role-label keys plus anything BuildNodeLabels/nodelabels.IsManagedLabelKey
+// would claim) differs between old and new.
+//
+// This deliberately does not cover arbitrary InstanceGroupSpec.NodeLabels
+// (userdata-provided) keys: Reconcile needs the Cluster/InstanceGroup loaded to
+// know what those keys even are, and loading them is exactly the VFS read this
+// predicate exists to avoid. A manual edit or removal of one of those labels
+// directly on a Node is instead picked up the next time its InstanceGroup is
+// reconciled for any other reason, and an edit to the InstanceGroup's
+// NodeLabels itself is still caught immediately via the per-IG watch fan-out
+// (see watchInstanceGroup), which enqueues every Node in the group regardless
+// of this predicate.
+func managedLabelsChanged(old, new map[string]string) bool {
+	seen := make(map[string]bool, len(old)+len(new))
+	for k := range old {
+		seen[k] = true
+	}
+	for k := range new {
+		seen[k] = true
+	}
+
+	for k := range seen {
+		if !nodelabels.IsManagedLabelKey(k) {
+			continue
+		}
+		oldValue, inOld := old[k]
+		newValue, inNew := new[k]
+		if inOld != inNew || oldValue != newValue {
+			return true
+		}
+	}
+	return false
+}
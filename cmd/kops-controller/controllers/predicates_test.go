@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestNodeReconcilePredicateUpdate(t *testing.T) {
+	grid := []struct {
+		name    string
+		oldNode *corev1.Node
+		newNode *corev1.Node
+		want    bool
+	}{
+		{
+			name:    "no-op heartbeat",
+			oldNode: node("n1", nil, ""),
+			newNode: node("n1", nil, ""),
+			want:    false,
+		},
+		{
+			name:    "providerID set",
+			oldNode: node("n1", nil, ""),
+			newNode: node("n1", nil, "aws:///us-east-1a/i-1"),
+			want:    true,
+		},
+		{
+			name:    "instancegroup label changed",
+			oldNode: node("n1", map[string]string{"kops.k8s.io/instancegroup": "nodes"}, ""),
+			newNode: node("n1", map[string]string{"kops.k8s.io/instancegroup": "nodes-spot"}, ""),
+			want:    true,
+		},
+		{
+			name:    "managed role label changed",
+			oldNode: node("n1", map[string]string{"node-role.kubernetes.io/node": ""}, ""),
+			newNode: node("n1", map[string]string{}, ""),
+			want:    true,
+		},
+		{
+			name:    "unrelated label changed",
+			oldNode: node("n1", map[string]string{"kubernetes.io/hostname": "a"}, ""),
+			newNode: node("n1", map[string]string{"kubernetes.io/hostname": "b"}, ""),
+			want:    false,
+		},
+	}
+
+	p := nodeReconcilePredicate()
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			got := p.Update(event.UpdateEvent{ObjectOld: g.oldNode, ObjectNew: g.newNode})
+			if got != g.want {
+				t.Errorf("got %v, want %v", got, g.want)
+			}
+		})
+	}
+}
+
+func TestNodeReconcilePredicateDelete(t *testing.T) {
+	p := nodeReconcilePredicate()
+	if p.Delete(event.DeleteEvent{Object: node("n1", nil, "")}) {
+		t.Errorf("expected delete events to be ignored")
+	}
+}
+
+func node(name string, labels map[string]string, providerID string) *corev1.Node {
+	n := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	}
+	n.Spec.ProviderID = providerID
+	return n
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodelabels
+
+import (
+	api "k8s.io/kops/pkg/apis/kops"
+)
+
+// BuildNodeAnnotations builds the annotations kOps manages for the given
+// InstanceGroup, as configured by its Cluster. It contributes no annotations
+// today - InstanceGroupSpec has no annotation-bearing field yet - and exists
+// purely as a named extension point so that future annotation sources
+// (topology hints, lifecycle metadata) have somewhere to plug in. Callers
+// should only ever apply the returned map as additions/updates, not use it to
+// decide what to prune: a real pruning pass needs its own declared prefix
+// (see ManagedTaintNamespace for taints) so it doesn't clobber annotations set
+// by anything else.
+func BuildNodeAnnotations(cluster *api.Cluster, ig *api.InstanceGroup) (map[string]string, error) {
+	return make(map[string]string), nil
+}
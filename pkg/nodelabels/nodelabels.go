@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodelabels computes the labels kOps manages on behalf of an
+// InstanceGroup, so that the kops-controller node controller and the
+// kubelet bootstrap flow agree on what a Node should look like.
+package nodelabels
+
+import (
+	api "k8s.io/kops/pkg/apis/kops"
+)
+
+const (
+	// RoleLabelMaster16 was the node-role label applied to master nodes prior to 1.20.
+	RoleLabelMaster16 = "node-role.kubernetes.io/master"
+	// RoleLabelAPIServer16 marks a node as running an API server.
+	RoleLabelAPIServer16 = "node-role.kubernetes.io/api-server"
+	// RoleLabelNode16 was the node-role label applied to regular nodes prior to 1.20.
+	RoleLabelNode16 = "node-role.kubernetes.io/node"
+	// RoleLabelControlPlane20 is the node-role label applied to control-plane nodes from 1.20 onwards.
+	RoleLabelControlPlane20 = "node-role.kubernetes.io/control-plane"
+)
+
+// LabelSchemaVersion is the current version of the label schema BuildNodeLabels
+// produces. It is recorded on Nodes (under the kops.k8s.io/label-schema-version
+// annotation) by the startup label migration so that later runs can skip Nodes
+// that are already known to be up to date.
+const LabelSchemaVersion = "2"
+
+// DeprecatedManagedLabels lists label keys that kOps used to apply to Nodes in
+// earlier releases but no longer does, kept here purely so the startup
+// migration (see cmd/kops-controller/controllers) can prune them from Nodes
+// that were labeled by an older kops-controller and haven't reconciled since.
+var DeprecatedManagedLabels = []string{
+	// Pre-1.16 scheme, superseded by RoleLabelMaster16/RoleLabelControlPlane20.
+	"kubernetes.io/role",
+	// Note: the "node-role.kubernetes.io/<lifecycle>-worker" labels are NOT
+	// listed here, even though they look like prime deprecation candidates -
+	// Reconcile still sets them from identity.InstanceLifecycle. Listing a
+	// label here while Reconcile keeps applying it makes the migration
+	// self-defeating: it deletes the label, stamps the Node as migrated, and
+	// the very next reconcile re-adds the label with no migration left to
+	// catch it.
+}
+
+// managedLabelPrefixes lists the label key prefixes that BuildNodeLabels (and the
+// other label sources built on top of it) are authoritative for. It is used by
+// callers - such as the node controller's reconcile predicates - that need to know
+// whether a Node event could plausibly change the set of labels we manage, without
+// having to load the Cluster/InstanceGroup config to compute the labels themselves.
+var managedLabelPrefixes = []string{
+	"node-role.kubernetes.io/",
+	"kops.k8s.io/",
+	"node.kubernetes.io/",
+}
+
+// IsManagedLabelKey reports whether key falls under a label domain that
+// BuildNodeLabels may set, so that callers can cheaply decide whether a label
+// change is one kOps cares about.
+func IsManagedLabelKey(key string) bool {
+	switch key {
+	case RoleLabelMaster16, RoleLabelAPIServer16, RoleLabelNode16, RoleLabelControlPlane20:
+		return true
+	}
+	for _, prefix := range managedLabelPrefixes {
+		if len(key) > len(prefix) && key[:len(prefix)] == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// ManagedTaintNamespace is the key prefix kOps uses for taints it considers itself
+// authoritative over by default, so that a reconciler pruning stale taints doesn't
+// remove taints placed by other actors (cluster-autoscaler, the user, webhooks).
+const ManagedTaintNamespace = "node.kops.k8s.io/"
+
+// BuildNodeLabels builds the labels for the given InstanceGroup, as configured by
+// its Cluster.
+func BuildNodeLabels(cluster *api.Cluster, ig *api.InstanceGroup) (map[string]string, error) {
+	labels := make(map[string]string)
+
+	for k, v := range ig.Spec.NodeLabels {
+		labels[k] = v
+	}
+
+	labels["kops.k8s.io/instancegroup"] = ig.Name
+
+	switch ig.Spec.Role {
+	case api.InstanceGroupRoleControlPlane, api.InstanceGroupRoleMaster:
+		labels[RoleLabelControlPlane20] = ""
+		labels[RoleLabelMaster16] = ""
+		if ig.Spec.Role == api.InstanceGroupRoleMaster || ig.Spec.Role == api.InstanceGroupRoleControlPlane {
+			labels[RoleLabelAPIServer16] = ""
+		}
+	case api.InstanceGroupRoleAPIServer:
+		labels[RoleLabelAPIServer16] = ""
+	default:
+		labels[RoleLabelNode16] = ""
+	}
+
+	return labels, nil
+}
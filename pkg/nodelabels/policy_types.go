@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodelabels
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupVersion is the API group and version used to register NodeLabelPolicy.
+var GroupVersion = schema.GroupVersion{Group: "kops.k8s.io", Version: "v1alpha1"}
+
+// SchemeBuilder collects the functions that add NodeLabelPolicy and
+// NodeLabelPolicyList to a runtime.Scheme.
+var SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+
+// AddToScheme adds NodeLabelPolicy and NodeLabelPolicyList to scheme. Callers
+// that want to opt in to NodeLabelPolicySource (see NewNodeLabelPolicySource)
+// must call this against their manager's scheme before the CRD-backed client
+// is used, or the cached client will fail with "no kind is registered".
+var AddToScheme = SchemeBuilder.AddToScheme
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(GroupVersion, &NodeLabelPolicy{}, &NodeLabelPolicyList{})
+	metav1.AddToGroupVersion(scheme, GroupVersion)
+	return nil
+}
+
+// NodeLabelPolicySpec declares labels that should be applied to every Node
+// matching NodeSelector, without requiring a kops-controller redeploy.
+type NodeLabelPolicySpec struct {
+	// NodeSelector selects the Nodes this policy applies to. A nil selector
+	// matches no Nodes.
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// Labels are the labels to apply to every selected Node.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NodeLabelPolicy lets cluster admins add labels to Nodes selected by a node
+// selector, without redeploying kops-controller.
+type NodeLabelPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NodeLabelPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NodeLabelPolicyList is a list of NodeLabelPolicy.
+type NodeLabelPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []NodeLabelPolicy `json:"items"`
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *NodeLabelPolicy) DeepCopyObject() runtime.Object {
+	out := &NodeLabelPolicy{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+		Spec: NodeLabelPolicySpec{
+			NodeSelector: in.Spec.NodeSelector.DeepCopy(),
+		},
+	}
+	if in.Spec.Labels != nil {
+		out.Spec.Labels = make(map[string]string, len(in.Spec.Labels))
+		for k, v := range in.Spec.Labels {
+			out.Spec.Labels[k] = v
+		}
+	}
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *NodeLabelPolicyList) DeepCopyObject() runtime.Object {
+	out := &NodeLabelPolicyList{
+		TypeMeta: in.TypeMeta,
+		ListMeta: in.ListMeta,
+	}
+	if in.Items != nil {
+		out.Items = make([]NodeLabelPolicy, len(in.Items))
+		for i := range in.Items {
+			out.Items[i] = *in.Items[i].DeepCopyObject().(*NodeLabelPolicy)
+		}
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodelabels
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/nodeidentity"
+)
+
+// Source contributes a set of labels for a Node. It generalizes BuildNodeLabels
+// (the hardcoded InstanceGroup projection) into one of potentially several
+// pluggable label contributors, so that the node controller can be a
+// general-purpose labeling subsystem rather than a single hardcoded projector.
+type Source interface {
+	// Prefix is the label key prefix this Source is authoritative for. A Source
+	// may only override a label contributed by an earlier Source in the pipeline
+	// if the key falls under its own Prefix; it may still contribute brand-new
+	// keys outside its Prefix. A Source that is authoritative over an unbounded
+	// set of keys (such as an admin-configured CRD) can return "".
+	Prefix() string
+
+	// Labels returns the labels this Source contributes for node.
+	Labels(ctx context.Context, node *corev1.Node, cluster *api.Cluster, ig *api.InstanceGroup, identity *nodeidentity.Info) (map[string]string, error)
+}
+
+// MergeSources runs each Source in order and merges their contributions,
+// applying the override rule documented on Source.Prefix: a later Source may
+// only clobber a key an earlier Source already set if the key is under the
+// later Source's own Prefix.
+func MergeSources(ctx context.Context, node *corev1.Node, cluster *api.Cluster, ig *api.InstanceGroup, identity *nodeidentity.Info, sources []Source) (map[string]string, error) {
+	merged := make(map[string]string)
+
+	for _, s := range sources {
+		labels, err := s.Labels(ctx, node, cluster, ig, identity)
+		if err != nil {
+			return nil, fmt.Errorf("error building labels from source %T: %w", s, err)
+		}
+
+		prefix := s.Prefix()
+		for k, v := range labels {
+			if _, exists := merged[k]; exists && prefix != "" && !strings.HasPrefix(k, prefix) {
+				// Another source already owns this key and this source isn't
+				// authoritative for it; leave the existing value in place.
+				continue
+			}
+			merged[k] = v
+		}
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodelabels
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/nodeidentity"
+)
+
+// CloudMetadataSource contributes labels derived from the cloud instance
+// identity: whether the instance is spot or on-demand, GA aliases for the
+// deprecated beta zone/region topology labels, and the instance type's family.
+type CloudMetadataSource struct{}
+
+var _ Source = CloudMetadataSource{}
+
+// Prefix implements Source.
+func (CloudMetadataSource) Prefix() string {
+	return "node.kubernetes.io/"
+}
+
+// betaToGATopologyLabels maps the deprecated beta failure-domain labels (still
+// set by some cloud providers' in-tree kubelet integrations) to their GA aliases.
+var betaToGATopologyLabels = map[string]string{
+	"failure-domain.beta.kubernetes.io/zone":   "topology.kubernetes.io/zone",
+	"failure-domain.beta.kubernetes.io/region": "topology.kubernetes.io/region",
+}
+
+// Labels implements Source.
+func (CloudMetadataSource) Labels(ctx context.Context, node *corev1.Node, cluster *api.Cluster, ig *api.InstanceGroup, identity *nodeidentity.Info) (map[string]string, error) {
+	labels := make(map[string]string)
+
+	if identity != nil && identity.InstanceLifecycle != "" {
+		labels["node.kubernetes.io/capacity"] = identity.InstanceLifecycle
+	}
+
+	for beta, ga := range betaToGATopologyLabels {
+		if v, ok := node.Labels[beta]; ok {
+			labels[ga] = v
+		}
+	}
+
+	if identity != nil && identity.InstanceType != "" {
+		if family := instanceTypeFamily(identity.InstanceType); family != "" {
+			labels["node.kubernetes.io/instance-type-family"] = family
+		}
+	}
+
+	return labels, nil
+}
+
+// instanceTypeFamily extracts the family portion of a cloud instance type, e.g.
+// "m5.large" -> "m5", "n2-standard-4" -> "n2".
+func instanceTypeFamily(instanceType string) string {
+	if i := strings.IndexAny(instanceType, ".-"); i > 0 {
+		return instanceType[:i]
+	}
+	return ""
+}
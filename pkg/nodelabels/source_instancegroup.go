@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodelabels
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/nodeidentity"
+)
+
+// InstanceGroupSource contributes the labels declared by the InstanceGroup
+// itself: its role, and the userdata-provided labels under
+// InstanceGroupSpec.NodeLabels. It is just BuildNodeLabels wrapped up as a
+// Source, kept as the first (and, prior to this change, only) entry in the
+// default pipeline so that every other Source layers on top of it.
+type InstanceGroupSource struct{}
+
+var _ Source = InstanceGroupSource{}
+
+// Prefix implements Source. The InstanceGroup can declare arbitrary userdata
+// labels, so it isn't scoped to a single namespace.
+func (InstanceGroupSource) Prefix() string {
+	return ""
+}
+
+// Labels implements Source.
+func (InstanceGroupSource) Labels(ctx context.Context, node *corev1.Node, cluster *api.Cluster, ig *api.InstanceGroup, identity *nodeidentity.Info) (map[string]string, error) {
+	return BuildNodeLabels(cluster, ig)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodelabels
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/nodeidentity"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NodeLabelPolicySource contributes labels declared by NodeLabelPolicy objects
+// whose NodeSelector matches the Node, letting cluster admins add labels without
+// redeploying kops-controller. It takes no Prefix, since admins may declare
+// whatever label keys their policies need.
+//
+// This source is opt-in, not part of the built-in pipeline (see
+// LegacyNodeReconciler.sources): it depends on the NodeLabelPolicy CRD and on
+// nodelabels.AddToScheme having been called against the manager's scheme, and
+// neither is true by default in every deployment of kops-controller.
+type NodeLabelPolicySource struct {
+	client client.Client
+}
+
+var _ Source = &NodeLabelPolicySource{}
+
+// NewNodeLabelPolicySource builds a NodeLabelPolicySource that reads
+// NodeLabelPolicy objects through c.
+func NewNodeLabelPolicySource(c client.Client) *NodeLabelPolicySource {
+	return &NodeLabelPolicySource{client: c}
+}
+
+// Prefix implements Source.
+func (s *NodeLabelPolicySource) Prefix() string {
+	return ""
+}
+
+// Labels implements Source.
+func (s *NodeLabelPolicySource) Labels(ctx context.Context, node *corev1.Node, cluster *api.Cluster, ig *api.InstanceGroup, identity *nodeidentity.Info) (map[string]string, error) {
+	var policies NodeLabelPolicyList
+	if err := s.client.List(ctx, &policies); err != nil {
+		// The NodeLabelPolicy CRD is optional; if it isn't installed, treat
+		// "no policies" as a no-op instead of failing every Node reconcile.
+		// Other errors, including an unregistered scheme, are returned.
+		if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error listing NodeLabelPolicy objects: %w", err)
+	}
+
+	result := make(map[string]string)
+	nodeLabels := labels.Set(node.Labels)
+
+	for _, policy := range policies.Items {
+		if policy.Spec.NodeSelector == nil {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(policy.Spec.NodeSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nodeSelector on NodeLabelPolicy %q: %w", policy.Name, err)
+		}
+
+		if !selector.Matches(nodeLabels) {
+			continue
+		}
+
+		for k, v := range policy.Spec.Labels {
+			result[k] = v
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodelabels
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	api "k8s.io/kops/pkg/apis/kops"
+)
+
+// BuildNodeTaints builds the taints for the given InstanceGroup, parsing the
+// "key=value:effect" or "key:effect" strings in InstanceGroupSpec.Taints the same
+// way the kubelet bootstrap flow does.
+func BuildNodeTaints(cluster *api.Cluster, ig *api.InstanceGroup) ([]corev1.Taint, error) {
+	var taints []corev1.Taint
+
+	for _, s := range ig.Spec.Taints {
+		taint, err := parseTaint(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid taint %q on InstanceGroup %q: %w", s, ig.Name, err)
+		}
+		taints = append(taints, taint)
+	}
+
+	return taints, nil
+}
+
+// parseTaint parses a taint string in the form "key=value:effect" or "key:effect".
+func parseTaint(s string) (corev1.Taint, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return corev1.Taint{}, fmt.Errorf("expected key[=value]:effect, got %q", s)
+	}
+
+	effect := corev1.TaintEffect(parts[1])
+	switch effect {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		return corev1.Taint{}, fmt.Errorf("unknown taint effect %q", parts[1])
+	}
+
+	kv := strings.SplitN(parts[0], "=", 2)
+	taint := corev1.Taint{
+		Key:    kv[0],
+		Effect: effect,
+	}
+	if len(kv) == 2 {
+		taint.Value = kv[1]
+	}
+
+	return taint, nil
+}
+
+// TaintKey identifies a taint by its key+effect, ignoring value, matching how the
+// apiserver treats key+effect (not value) as the taint's identity.
+func TaintKey(t corev1.Taint) string {
+	return t.Key + ":" + string(t.Effect)
+}
+
+// IsManagedTaintKey reports whether key falls under the taint namespace kOps
+// manages by default. Reconcilers should additionally consult their own
+// ManagedTaintPrefixes before pruning a taint that isn't present in the desired set.
+func IsManagedTaintKey(key string) bool {
+	return strings.HasPrefix(key, ManagedTaintNamespace)
+}
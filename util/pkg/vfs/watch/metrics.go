@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Metrics tracks cache hit/miss/invalidation counts for a Watcher, so that
+// operators can see how effective the informer-style invalidation is
+// compared to the previous TTL-only cache. The collectors are process-wide
+// (registered once, regardless of how many Watchers are constructed), since
+// they're registered against the global controller-runtime registry and
+// aggregate across every Watcher in the process.
+type Metrics struct {
+	hits          prometheus.Counter
+	misses        prometheus.Counter
+	invalidations prometheus.Counter
+}
+
+var (
+	metricsOnce   sync.Once
+	sharedMetrics *Metrics
+)
+
+func newMetrics() *Metrics {
+	metricsOnce.Do(func() {
+		sharedMetrics = &Metrics{
+			hits: prometheus.NewCounter(prometheus.CounterOpts{
+				Name: "vfs_watch_cache_hits_total",
+				Help: "Number of vfs watch polls that found no change since the last poll.",
+			}),
+			misses: prometheus.NewCounter(prometheus.CounterOpts{
+				Name: "vfs_watch_cache_misses_total",
+				Help: "Number of vfs watch polls that failed to fetch a fingerprint for the watched path.",
+			}),
+			invalidations: prometheus.NewCounter(prometheus.CounterOpts{
+				Name: "vfs_watch_cache_invalidations_total",
+				Help: "Number of times a watched vfs path was observed to have changed.",
+			}),
+		}
+
+		metrics.Registry.MustRegister(sharedMetrics.hits, sharedMetrics.misses, sharedMetrics.invalidations)
+	})
+
+	return sharedMetrics
+}
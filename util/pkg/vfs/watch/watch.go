@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package watch provides a lightweight, informer-style layer over vfs.Path
+// that notices when a backing object changes and invokes a callback, instead
+// of relying purely on a TTL-based cache.
+package watch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// DefaultResyncInterval is the fallback poll interval used when a caller
+// does not specify one. Even on backends where we can cheaply detect
+// changes, we still fall back to a resync so that we self-heal from a
+// missed or failed notification.
+const DefaultResyncInterval = 30 * time.Second
+
+// ETagger is implemented by vfs.Path implementations that can report a
+// cheap-to-fetch identifier for the current version of the object (for
+// example the S3 or GCS generation/ETag), without reading the object body.
+type ETagger interface {
+	ETag() (string, error)
+}
+
+// OnChangeFunc is invoked when a watched path's fingerprint changes.
+type OnChangeFunc func(ctx context.Context, p vfs.Path)
+
+// fingerprint identifies the observed version of a watched object.
+type fingerprint struct {
+	etag    string
+	modTime time.Time
+}
+
+type watchedPath struct {
+	path       vfs.Path
+	onChange   OnChangeFunc
+	known      fingerprint
+	knownValid bool
+}
+
+// Watcher polls a set of vfs.Path objects for changes and invokes a
+// callback when a change is detected, instead of waiting for a TTL to
+// expire. It is intended to sit alongside (not replace) vfs.Cache: the
+// cache still holds the decoded object, but the Watcher is responsible for
+// evicting stale cache entries as soon as it observes a change.
+type Watcher struct {
+	mu       sync.Mutex
+	interval time.Duration
+	watches  map[string]*watchedPath
+
+	metrics *Metrics
+}
+
+// NewWatcher builds a Watcher that polls every resyncInterval. A zero
+// resyncInterval uses DefaultResyncInterval.
+func NewWatcher(resyncInterval time.Duration) *Watcher {
+	if resyncInterval <= 0 {
+		resyncInterval = DefaultResyncInterval
+	}
+	return &Watcher{
+		interval: resyncInterval,
+		watches:  make(map[string]*watchedPath),
+		metrics:  newMetrics(),
+	}
+}
+
+// Watch registers p to be polled for changes; onChange is called (from the
+// Watcher's polling goroutine) whenever the fingerprint of p changes,
+// including the first time it is observed after Watch is called.
+func (w *Watcher) Watch(p vfs.Path, onChange OnChangeFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.watches[p.Path()] = &watchedPath{
+		path:     p,
+		onChange: onChange,
+	}
+}
+
+// Unwatch stops polling p.
+func (w *Watcher) Unwatch(p vfs.Path) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.watches, p.Path())
+}
+
+// Start runs the poll loop until ctx is cancelled.
+func (w *Watcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.poll(ctx)
+		}
+	}
+}
+
+// poll checks every watched path once, invoking onChange for any whose
+// fingerprint has changed since the last poll.
+func (w *Watcher) poll(ctx context.Context) {
+	w.mu.Lock()
+	snapshot := make([]*watchedPath, 0, len(w.watches))
+	for _, wp := range w.watches {
+		snapshot = append(snapshot, wp)
+	}
+	w.mu.Unlock()
+
+	for _, wp := range snapshot {
+		fp, err := fingerprintOf(wp.path)
+		if err != nil {
+			klog.Warningf("unable to fingerprint %s, will retry on next resync: %v", wp.path, err)
+			w.metrics.misses.Inc()
+			continue
+		}
+
+		w.mu.Lock()
+		changed := !wp.knownValid || fp != wp.known
+		wp.known = fp
+		wp.knownValid = true
+		w.mu.Unlock()
+
+		if changed {
+			w.metrics.invalidations.Inc()
+			wp.onChange(ctx, wp.path)
+		} else {
+			w.metrics.hits.Inc()
+		}
+	}
+}
+
+// fingerprintOf returns a cheap-to-compute identifier for the current
+// version of p, preferring a provider ETag/generation where available and
+// falling back to the modification time exposed by local/file backends.
+func fingerprintOf(p vfs.Path) (fingerprint, error) {
+	if et, ok := p.(ETagger); ok {
+		tag, err := et.ETag()
+		if err != nil {
+			return fingerprint{}, err
+		}
+		return fingerprint{etag: tag}, nil
+	}
+
+	mtime, err := p.LastModified()
+	if err != nil {
+		return fingerprint{}, err
+	}
+	return fingerprint{modTime: mtime}, nil
+}